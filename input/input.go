@@ -0,0 +1,69 @@
+// Package input 提供一组共享同一个标准输入 scanner 的读取辅助函数，
+// 统一处理 EOF、非法输入重新提示、以及 Windows 换行符的裁剪。
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// stdin 是包级别共享的 scanner，所有辅助函数都从它读取，
+// 避免像原来那样在每个函数里各自创建一个 bufio.NewScanner 而丢失缓冲状态。
+var stdin = bufio.NewScanner(os.Stdin)
+
+// scanLine 输出 prompt 并读取一行，遇到 EOF 时 ok 返回 false。
+func scanLine(prompt string) (line string, ok bool) {
+	fmt.Print(prompt)
+	if !stdin.Scan() {
+		return "", false
+	}
+	return strings.TrimRight(stdin.Text(), "\r\n"), true
+}
+
+// InputString 输出 prompt 并读取一行输入。ok 在遇到 EOF（标准输入被关闭
+// 或非交互式管道读完）时返回 false，调用方必须据此退出，而不是继续读取。
+func InputString(prompt string) (line string, ok bool) {
+	return scanLine(prompt)
+}
+
+// InputInt 循环提示，直到用户输入 [min, max] 区间内的整数；
+// 遇到 EOF 时不再无限重试，直接返回 min。
+func InputInt(prompt string, min, max int) int {
+	for {
+		line, ok := scanLine(prompt)
+		if !ok {
+			return min
+		}
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			fmt.Println("输入的不是数字，请重新输入")
+			continue
+		}
+		if n < min || n > max {
+			fmt.Println("输入的数字不合法，请重新输入")
+			continue
+		}
+		return n
+	}
+}
+
+// Confirm 循环提示，直到用户输入 y/n（不区分大小写）；遇到 EOF 时返回 false。
+func Confirm(prompt string) bool {
+	for {
+		line, ok := scanLine(prompt + " (y/n): ")
+		if !ok {
+			return false
+		}
+		switch strings.ToLower(line) {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		default:
+			fmt.Println("请输入 y 或 n")
+		}
+	}
+}
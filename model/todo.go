@@ -0,0 +1,162 @@
+// Package model 定义待办事项的数据结构、内联标注解析和排序规则，
+// 供 CLI 以及未来的存储实现共用同一套 schema。
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dueLayout 是 @2025-01-15 这类到期日标注使用的日期格式。
+const dueLayout = "2006-01-02"
+
+// TodoItem 表示一条待办事项，Due 为零值代表没有设置到期日。
+type TodoItem struct {
+	ID        int
+	Text      string
+	Tags      []string
+	Priority  int
+	Due       time.Time
+	Done      bool
+	CreatedAt time.Time
+}
+
+// New 从用户输入的原始文本中解析出 #标签、!优先级、@到期日 标注，
+// 构造一条待办事项，剩余的普通词语作为 Text。
+func New(id int, raw string, createdAt time.Time) TodoItem {
+	text, tags, priority, due := ParseAnnotations(raw)
+	return TodoItem{
+		ID:        id,
+		Text:      text,
+		Tags:      tags,
+		Priority:  priority,
+		Due:       due,
+		CreatedAt: createdAt,
+	}
+}
+
+// ParseAnnotations 从 raw 中提取 #tag、!1..!3、@YYYY-MM-DD 标注，
+// 返回去掉标注后的正文，以及解析出的标签、优先级、到期日。
+// 无法识别为标注的 token（例如格式错误的 @日期）会原样保留在正文中。
+func ParseAnnotations(raw string) (text string, tags []string, priority int, due time.Time) {
+	var words []string
+	for _, token := range strings.Fields(raw) {
+		switch {
+		case strings.HasPrefix(token, "#") && len(token) > 1:
+			tags = append(tags, token[1:])
+		case len(token) == 2 && token[0] == '!' && token[1] >= '1' && token[1] <= '3':
+			priority = int(token[1] - '0')
+		case strings.HasPrefix(token, "@"):
+			if parsed, err := time.Parse(dueLayout, token[1:]); err == nil {
+				due = parsed
+				continue
+			}
+			words = append(words, token)
+		default:
+			words = append(words, token)
+		}
+	}
+	return strings.Join(words, " "), tags, priority, due
+}
+
+// HasTag 判断待办事项是否带有指定标签（不含 # 前缀）。
+func HasTag(item TodoItem, tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+	for _, t := range item.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByTag 返回带有指定标签的待办事项。
+func FilterByTag(items []TodoItem, tag string) []TodoItem {
+	var out []TodoItem
+	for _, item := range items {
+		if HasTag(item, tag) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// IsDueToday 判断待办事项的到期日是否是 now 所在的那一天。
+func IsDueToday(item TodoItem, now time.Time) bool {
+	if item.Due.IsZero() {
+		return false
+	}
+	y1, m1, d1 := item.Due.Date()
+	y2, m2, d2 := now.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// FilterDueToday 返回到期日是今天的待办事项。
+func FilterDueToday(items []TodoItem, now time.Time) []TodoItem {
+	var out []TodoItem
+	for _, item := range items {
+		if IsDueToday(item, now) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// ByPriority 按优先级从高到低排序（!1 最高），没有设置优先级的排在最后。
+func ByPriority(items []TodoItem) func(i, j int) bool {
+	rank := func(p int) int {
+		if p == 0 {
+			return 4
+		}
+		return p
+	}
+	return func(i, j int) bool {
+		return rank(items[i].Priority) < rank(items[j].Priority)
+	}
+}
+
+// ByDue 按到期日从早到晚排序，没有设置到期日的排在最后。
+func ByDue(items []TodoItem) func(i, j int) bool {
+	return func(i, j int) bool {
+		a, b := items[i].Due, items[j].Due
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero()
+		}
+		return a.Before(b)
+	}
+}
+
+// ByCreated 按创建时间从早到晚排序。
+func ByCreated(items []TodoItem) func(i, j int) bool {
+	return func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	}
+}
+
+// FormatTable 把待办事项渲染成一张包含 ID、状态、优先级、到期日、标签、内容的表格。
+func FormatTable(items []TodoItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-4s %-4s %-6s %-10s %-16s %s\n", "ID", "状态", "优先级", "到期日", "标签", "内容")
+	for _, item := range items {
+		status := "[ ]"
+		if item.Done {
+			status = "[x]"
+		}
+		priority := "-"
+		if item.Priority > 0 {
+			priority = fmt.Sprintf("P%d", item.Priority)
+		}
+		due := "-"
+		if !item.Due.IsZero() {
+			due = item.Due.Format(dueLayout)
+		}
+		tags := strings.Join(item.Tags, ",")
+		if tags == "" {
+			tags = "-"
+		}
+		fmt.Fprintf(&b, "%-4s %-4s %-6s %-10s %-16s %s\n", strconv.Itoa(item.ID), status, priority, due, tags, item.Text)
+	}
+	return b.String()
+}
@@ -2,28 +2,180 @@ package main
 
 import (
 	"bufio"
+	"encoding/csv"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
+
+	"mind-test-platform/input"
+	"mind-test-platform/model"
 )
 
+// maxSnapshots 控制备份文件的保留数量，超出的旧备份会被清理掉。
+const maxSnapshots = 3
+
+// dueDateLayout 是 CSV 中到期日列使用的日期格式，与标注 @2025-01-15 保持一致。
+const dueDateLayout = "2006-01-02"
+
 func main() {
-	todoList := []string{}
-	tools(todoList)
+	file := flag.String("file", "todo.csv", "待办事项数据文件路径")
+	flag.Parse()
+
+	todoList, err := load(*file)
+	if err != nil {
+		fmt.Println("加载数据失败，将从空列表开始:", err)
+		todoList = []model.TodoItem{}
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		tools(todoList, *file)
+		return
+	}
+	runCLI(args, todoList, *file)
+}
+
+// runCLI 处理 `todo add/ls/rm/done ...` 这类非交互式子命令，
+// 复用与交互菜单相同的 add/view/delete 核心函数，便于脚本化调用。
+func runCLI(args []string, todoList []model.TodoItem, file string) {
+	command, rest := args[0], args[1:]
+	dirty := true
+
+	switch command {
+	case "add":
+		texts := cliAddTexts(rest)
+		if len(texts) == 0 {
+			fmt.Println("用法: todo add \"内容\" 或 todo add - （从标准输入读取多行）")
+			os.Exit(1)
+		}
+		for _, text := range texts {
+			todoList = addItem(todoList, text)
+		}
+	case "ls", "view":
+		cliView(rest, todoList)
+		dirty = false
+	case "rm":
+		idx, ok := cliIndexByID(rest, todoList, "todo rm <ID>")
+		if !ok {
+			os.Exit(1)
+		}
+		todoList = deleteAt(todoList, idx)
+	case "done":
+		idx, ok := cliIndexByID(rest, todoList, "todo done <ID>")
+		if !ok {
+			os.Exit(1)
+		}
+		todoList = toggleDoneAt(todoList, idx)
+	default:
+		fmt.Println("未知命令:", command)
+		os.Exit(1)
+	}
+
+	if dirty {
+		if err := save(file, todoList); err != nil {
+			fmt.Println("保存失败:", err)
+		}
+	}
+}
+
+// cliAddTexts 解析 `add` 子命令的参数：显式传入 "-" 或未传参数时检测到
+// 管道输入，都从标准输入按行读取；否则把剩余参数拼成一条待办内容。
+func cliAddTexts(rest []string) []string {
+	if len(rest) > 0 && rest[0] == "-" {
+		return readLines(os.Stdin)
+	}
+	if len(rest) == 0 {
+		if isPipedStdin() {
+			return readLines(os.Stdin)
+		}
+		return nil
+	}
+	return []string{strings.Join(rest, " ")}
+}
+
+// cliIndexByID 解析 `rm`/`done` 子命令的 ID 参数，并在 todoList 中定位该 ID
+// 对应的下标。之所以按 ID 而不是按位置寻址，是因为 `ls`/`view` 可能带
+// --sort=/#tag 过滤后打印出与存储顺序不同的表格，位置号会对不上；
+// ID 列是唯一在任何视图下都保持稳定的标识。
+func cliIndexByID(rest []string, todoList []model.TodoItem, usage string) (int, bool) {
+	if len(rest) != 1 {
+		fmt.Println("用法:", usage)
+		return 0, false
+	}
+	id, err := strconv.Atoi(rest[0])
+	if err != nil || id <= 0 {
+		fmt.Println("ID 不合法:", rest[0])
+		return 0, false
+	}
+	for i, item := range todoList {
+		if item.ID == id {
+			return i, true
+		}
+	}
+	fmt.Println("未找到 ID 为", id, "的事项")
+	return 0, false
+}
+
+// isPipedStdin 判断标准输入是否来自管道/重定向而非交互终端。
+func isPipedStdin() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// readLines 从 r 中按行读取非空内容，用于 `todo add -` 的管道输入模式。
+func readLines(r io.Reader) []string {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
 }
 
-func tools(todoList []string) []string {
-	scanner := bufio.NewScanner(os.Stdin)
+func tools(todoList []model.TodoItem, file string) []model.TodoItem {
+	persist := func() {
+		if err := save(file, todoList); err != nil {
+			fmt.Println("保存失败:", err)
+		}
+	}
 	for {
-		fmt.Println("--- 待办事项列表 ---\n1. 添加新事项\n2. 查看所有事项\n3. 退出程序\n请输入你的选择: ")
-		scanner.Scan()
-		input := scanner.Text()
-		switch input {
+		choice, ok := input.InputString("--- 待办事项列表 ---\n1. 添加新事项\n2. 查看所有事项\n3. 删除事项\n4. 标记完成/未完成\n5. 编辑事项内容\n6. 上移事项\n7. 下移事项\n8. 退出程序\n请输入你的选择: ")
+		if !ok {
+			return todoList
+		}
+		switch choice {
 		case "1":
 			todoList = add(todoList)
+			persist()
 		case "2":
 			view(todoList)
 		case "3":
+			todoList = delete(todoList)
+			persist()
+		case "4":
+			todoList = toggleDone(todoList)
+			persist()
+		case "5":
+			todoList = edit(todoList)
+			persist()
+		case "6":
+			todoList = move(todoList, -1)
+			persist()
+		case "7":
+			todoList = move(todoList, 1)
+			persist()
+		case "8":
 			os.Exit(0)
 		default:
 			fmt.Println("输入的数字不合法，请重新输入")
@@ -31,36 +183,352 @@ func tools(todoList []string) []string {
 	}
 }
 
-func add(todoList []string) []string {
-	fmt.Println("请输入待办事项: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	input := scanner.Text()
-	todoList = append(todoList, input)
-	fmt.Printf("添加成功！当前待办事项是 %v\n", todoList)
+func add(todoList []model.TodoItem) []model.TodoItem {
+	text, ok := input.InputString("请输入待办事项: \n")
+	if !ok {
+		return todoList
+	}
+	return addItem(todoList, text)
+}
+
+// addItem 是 add 的核心逻辑，不涉及任何标准输入读取。raw 中的
+// #标签、!优先级、@到期日 标注由 model.New 解析，交互菜单和 CLI 子命令都通过它追加待办事项。
+func addItem(todoList []model.TodoItem, raw string) []model.TodoItem {
+	item := model.New(nextID(todoList), raw, time.Now())
+	todoList = append(todoList, item)
+	fmt.Print(model.FormatTable(todoList))
 	return todoList
 }
 
-func view(todoList []string) {
-	fmt.Printf("当前待办事项是 %v\n", todoList)
+// view 打印全部待办事项，不做任何过滤或排序。
+func view(todoList []model.TodoItem) {
+	fmt.Print(model.FormatTable(todoList))
 }
 
-func delete(todoList []string) {
-	fmt.Printf("当前待办列表是 %v\n 请问需要删除哪一个", todoList)
-	for {
-		scanner := bufio.NewScanner(os.Stdin)
-		scanner.Scan()
-		input := scanner.Text()
-		index, err := strconv.Atoi(input)
-		if err == nil {
-			if index > 0 && index <= len(todoList) {
-				//待实现
-				break
-			} else {
-				fmt.Println("输入的数字不合法，请重新输入")
-			}
-		} else {
-			fmt.Println("输入的不是数字，请重新输入")
+// cliView 处理 CLI 的 `view`/`ls` 子命令参数：#tag 按标签过滤，
+// --due-today 只看今天到期的，--sort=priority|due|created 指定排序方式。
+// 不传 --sort= 时保持存储顺序不变，和交互菜单里 view() 的行为一致，
+// 不会覆盖用户通过“上移/下移”手动调整过的顺序。
+func cliView(args []string, todoList []model.TodoItem) {
+	items := todoList
+	sortKey := ""
+	for _, a := range args {
+		switch {
+		case strings.HasPrefix(a, "#"):
+			items = model.FilterByTag(items, a)
+		case a == "--due-today":
+			items = model.FilterDueToday(items, time.Now())
+		case strings.HasPrefix(a, "--sort="):
+			sortKey = strings.TrimPrefix(a, "--sort=")
+		default:
+			fmt.Println("忽略无法识别的参数:", a)
+		}
+	}
+
+	sorted := make([]model.TodoItem, len(items))
+	copy(sorted, items)
+	switch sortKey {
+	case "":
+		// 未指定 --sort，保持存储顺序。
+	case "priority":
+		sort.Slice(sorted, model.ByPriority(sorted))
+	case "due":
+		sort.Slice(sorted, model.ByDue(sorted))
+	case "created":
+		sort.Slice(sorted, model.ByCreated(sorted))
+	default:
+		fmt.Println("未知的排序方式:", sortKey)
+	}
+	fmt.Print(model.FormatTable(sorted))
+}
+
+// nextID 返回下一个可用的 ID，保证删除、重排后依旧不会重复。
+func nextID(todoList []model.TodoItem) int {
+	maxID := 0
+	for _, item := range todoList {
+		if item.ID > maxID {
+			maxID = item.ID
+		}
+	}
+	return maxID + 1
+}
+
+// promptIndex 显示列表并读取一个合法的序号（从 1 开始），返回其切片下标。
+func promptIndex(todoList []model.TodoItem, prompt string) int {
+	fmt.Print(model.FormatTable(todoList))
+	return input.InputInt(prompt, 1, len(todoList)) - 1
+}
+
+func delete(todoList []model.TodoItem) []model.TodoItem {
+	if len(todoList) == 0 {
+		fmt.Println("待办列表为空，无需删除")
+		return todoList
+	}
+	idx := promptIndex(todoList, "请问需要删除哪一个")
+	return deleteAt(todoList, idx)
+}
+
+// deleteAt 是 delete 的核心逻辑，按 0 基下标删除一项，
+// 交互菜单和 CLI 子命令都通过它执行删除。
+func deleteAt(todoList []model.TodoItem, idx int) []model.TodoItem {
+	todoList = append(todoList[:idx], todoList[idx+1:]...)
+	fmt.Println("删除成功！")
+	fmt.Print(model.FormatTable(todoList))
+	return todoList
+}
+
+// toggleDone 切换指定事项的完成状态。
+func toggleDone(todoList []model.TodoItem) []model.TodoItem {
+	if len(todoList) == 0 {
+		fmt.Println("待办列表为空，无需标记")
+		return todoList
+	}
+	idx := promptIndex(todoList, "请问需要标记哪一个")
+	return toggleDoneAt(todoList, idx)
+}
+
+// toggleDoneAt 是 toggleDone 的核心逻辑，按 0 基下标切换完成状态，
+// 交互菜单和 CLI 子命令都通过它执行标记。
+func toggleDoneAt(todoList []model.TodoItem, idx int) []model.TodoItem {
+	todoList[idx].Done = !todoList[idx].Done
+	fmt.Println("标记成功！")
+	fmt.Print(model.FormatTable(todoList))
+	return todoList
+}
+
+// edit 就地修改指定事项的文本内容。
+func edit(todoList []model.TodoItem) []model.TodoItem {
+	if len(todoList) == 0 {
+		fmt.Println("待办列表为空，无需编辑")
+		return todoList
+	}
+	idx := promptIndex(todoList, "请问需要编辑哪一个")
+	text, ok := input.InputString("请输入新的内容: \n")
+	if !ok {
+		return todoList
+	}
+	todoList[idx].Text = text
+	fmt.Println("编辑成功！")
+	fmt.Print(model.FormatTable(todoList))
+	return todoList
+}
+
+// move 将指定事项与相邻位置交换，offset 为 -1 表示上移，+1 表示下移。
+func move(todoList []model.TodoItem, offset int) []model.TodoItem {
+	if len(todoList) < 2 {
+		fmt.Println("待办列表事项不足，无法移动")
+		return todoList
+	}
+	idx := promptIndex(todoList, "请问需要移动哪一个")
+	target := idx + offset
+	if target < 0 || target >= len(todoList) {
+		fmt.Println("已经在边界，无法继续移动")
+		return todoList
+	}
+	todoList[idx], todoList[target] = todoList[target], todoList[idx]
+	fmt.Println("移动成功！")
+	fmt.Print(model.FormatTable(todoList))
+	return todoList
+}
+
+// load 从 path 读取待办事项列表。文件不存在或内容损坏都视作空列表，
+// 而不是让程序在启动时直接报错退出。
+func load(path string) ([]model.TodoItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.TodoItem{}, nil
+		}
+		return []model.TodoItem{}, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	// 允许各行列数不同，列数不符合任何格式的行交给 parseRecord 逐行跳过。
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return []model.TodoItem{}, err
+	}
+
+	todoList := make([]model.TodoItem, 0, len(records))
+	for i, record := range records {
+		item, ok := parseRecord(record)
+		if !ok {
+			fmt.Printf("警告：忽略第 %d 行无法识别的记录: %v\n", i+1, record)
+			continue
+		}
+		todoList = append(todoList, item)
+	}
+	return todoList, nil
+}
+
+// csvColumns 是当前 CSV 格式每条待办事项的列数：
+// id, text, tags（用 ; 分隔）, priority, due（YYYY-MM-DD，可为空）, done, createdAt。
+const csvColumns = 7
+
+// legacyCSVColumns 是 chunk0-2/chunk0-3/chunk0-4 阶段使用的旧格式列数：
+// id, text, done, createdAt。旧文件里没有 tags/priority/due，读取时按空值补齐。
+const legacyCSVColumns = 4
+
+// parseRecord 把一行 CSV 记录解析成 model.TodoItem。同时兼容当前格式和
+// 升级前的旧格式，格式都不匹配的行会被跳过（由调用方记录警告）。
+func parseRecord(record []string) (model.TodoItem, bool) {
+	switch len(record) {
+	case csvColumns:
+		return parseCurrentRecord(record)
+	case legacyCSVColumns:
+		return parseLegacyRecord(record)
+	default:
+		return model.TodoItem{}, false
+	}
+}
+
+func parseCurrentRecord(record []string) (model.TodoItem, bool) {
+	id, err := strconv.Atoi(record[0])
+	if err != nil {
+		return model.TodoItem{}, false
+	}
+	var tags []string
+	if record[2] != "" {
+		tags = strings.Split(record[2], ";")
+	}
+	priority, err := strconv.Atoi(record[3])
+	if err != nil {
+		return model.TodoItem{}, false
+	}
+	var due time.Time
+	if record[4] != "" {
+		due, err = time.Parse(dueDateLayout, record[4])
+		if err != nil {
+			return model.TodoItem{}, false
+		}
+	}
+	done, err := strconv.ParseBool(record[5])
+	if err != nil {
+		return model.TodoItem{}, false
+	}
+	createdAt, err := time.Parse(time.RFC3339, record[6])
+	if err != nil {
+		return model.TodoItem{}, false
+	}
+	return model.TodoItem{
+		ID:        id,
+		Text:      record[1],
+		Tags:      tags,
+		Priority:  priority,
+		Due:       due,
+		Done:      done,
+		CreatedAt: createdAt,
+	}, true
+}
+
+// parseLegacyRecord 解析升级前的 4 列格式（id, text, done, createdAt），
+// 没有 tags/priority/due 的老数据文件仍然能被正常加载，不会被静默丢弃。
+func parseLegacyRecord(record []string) (model.TodoItem, bool) {
+	id, err := strconv.Atoi(record[0])
+	if err != nil {
+		return model.TodoItem{}, false
+	}
+	done, err := strconv.ParseBool(record[2])
+	if err != nil {
+		return model.TodoItem{}, false
+	}
+	createdAt, err := time.Parse(time.RFC3339, record[3])
+	if err != nil {
+		return model.TodoItem{}, false
+	}
+	return model.TodoItem{ID: id, Text: record[1], Done: done, CreatedAt: createdAt}, true
+}
+
+// save 把待办事项列表写入 path，并额外生成一份带时间戳的快照备份，
+// 只保留最近的 maxSnapshots 份，旧的自动清理掉。
+func save(path string, todos []model.TodoItem) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := writeCSV(f, todos); err != nil {
+		return err
+	}
+
+	if err := writeSnapshot(path, todos); err != nil {
+		return err
+	}
+	return pruneSnapshots(path)
+}
+
+func writeCSV(f *os.File, todos []model.TodoItem) error {
+	w := csv.NewWriter(f)
+	for _, todo := range todos {
+		due := ""
+		if !todo.Due.IsZero() {
+			due = todo.Due.Format(dueDateLayout)
+		}
+		record := []string{
+			strconv.Itoa(todo.ID),
+			todo.Text,
+			strings.Join(todo.Tags, ";"),
+			strconv.Itoa(todo.Priority),
+			due,
+			strconv.FormatBool(todo.Done),
+			todo.CreatedAt.Format(time.RFC3339),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeSnapshot 写入形如 <path>.<unix>.bak（例如 todo.csv.<unix>.bak）的滚动备份文件。
+func writeSnapshot(path string, todos []model.TodoItem) error {
+	snapshotPath := fmt.Sprintf("%s.%d.bak", path, time.Now().Unix())
+	f, err := os.Create(snapshotPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return writeCSV(f, todos)
+}
+
+// pruneSnapshots 只保留 path 对应的最近 maxSnapshots 份快照，删除更早的。
+func pruneSnapshots(path string) error {
+	dir := "."
+	if idx := strings.LastIndex(path, "/"); idx != -1 {
+		dir = path[:idx]
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	base := path
+	if idx := strings.LastIndex(path, "/"); idx != -1 {
+		base = path[idx+1:]
+	}
+	prefix := base + "."
+	const suffix = ".bak"
+
+	var snapshots []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, suffix) {
+			snapshots = append(snapshots, name)
+		}
+	}
+	sort.Strings(snapshots)
+
+	for len(snapshots) > maxSnapshots {
+		stale := snapshots[0]
+		snapshots = snapshots[1:]
+		if err := os.Remove(dir + "/" + stale); err != nil {
+			return err
 		}
 	}
+	return nil
 }